@@ -0,0 +1,120 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// cacheEntry is the on-disk representation of a cached search result.
+type cacheEntry struct {
+	Time  time.Time `json:"time"`
+	Packs []pack    `json:"packs"`
+}
+
+// cacheDir returns the directory gosearch stores cached search results
+// in, creating it if it doesn't already exist.
+func cacheDir() (string, error) {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".cache")
+	}
+	dir := filepath.Join(base, "gosearch")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func cachePath(backend, q string) (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, cacheFileName(backend, q)), nil
+}
+
+// cacheFileName derives a cache file name from the backend and query. The
+// query is hashed rather than character-filtered so that queries which
+// differ only in punctuation or whitespace (e.g. "foo bar" vs "foo_bar")
+// don't collide on the same cache file.
+func cacheFileName(backend, q string) string {
+	sum := sha256.Sum256([]byte(q))
+	return fmt.Sprintf("%s-%x.json", backend, sum)
+}
+
+func readCache(path string) (cacheEntry, bool) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cacheEntry{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return cacheEntry{}, false
+	}
+	return entry, true
+}
+
+func writeCache(path string, entry cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	_ = ioutil.WriteFile(path, data, 0o644)
+}
+
+// cachingSearcher wraps a Searcher with an on-disk cache keyed by
+// (backend, query). Entries older than ttl are treated as a miss. If
+// refresh is set the cache is always bypassed, but still written to.
+type cachingSearcher struct {
+	Searcher
+	ttl     time.Duration
+	refresh bool
+}
+
+func (c cachingSearcher) Search(q string) ([]pack, error) {
+	path, err := cachePath(c.Name(), q)
+	if err != nil {
+		return c.Searcher.Search(q)
+	}
+
+	if !c.refresh {
+		if entry, ok := readCache(path); ok && time.Since(entry.Time) < c.ttl {
+			return entry.Packs, nil
+		}
+	}
+
+	packs, err := c.Searcher.Search(q)
+	if err != nil {
+		return nil, err
+	}
+
+	writeCache(path, cacheEntry{Time: time.Now(), Packs: packs})
+	return packs, nil
+}
+
+// listOnlySearcher reads results only from the cache, for -list's offline
+// mode. It never touches the network, regardless of TTL.
+type listOnlySearcher struct {
+	Searcher
+}
+
+func (l listOnlySearcher) Search(q string) ([]pack, error) {
+	path, err := cachePath(l.Name(), q)
+	if err != nil {
+		return nil, err
+	}
+	entry, ok := readCache(path)
+	if !ok {
+		return nil, fmt.Errorf("%s: no cached results for %q", l.Name(), q)
+	}
+	return entry.Packs, nil
+}
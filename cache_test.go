@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCacheFileNameNoCollision(t *testing.T) {
+	a := cacheFileName("pkggodev", "foo bar")
+	b := cacheFileName("pkggodev", "foo_bar")
+	if a == b {
+		t.Fatalf("cacheFileName collided for distinct queries %q and %q: both -> %q", "foo bar", "foo_bar", a)
+	}
+}
+
+func TestCacheFileNameStableAndNamespacedByBackend(t *testing.T) {
+	if cacheFileName("pkggodev", "foo") != cacheFileName("pkggodev", "foo") {
+		t.Fatal("cacheFileName is not stable for the same input")
+	}
+	if cacheFileName("pkggodev", "foo") == cacheFileName("godoc", "foo") {
+		t.Fatal("cacheFileName did not namespace by backend")
+	}
+}
+
+func TestReadWriteCacheRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := dir + "/entry.json"
+
+	want := cacheEntry{Time: time.Now().Truncate(time.Second), Packs: []pack{{Path: "example.com/foo", Stars: 3}}}
+	writeCache(path, want)
+
+	got, ok := readCache(path)
+	if !ok {
+		t.Fatal("readCache reported no entry after writeCache")
+	}
+	if !got.Time.Equal(want.Time) || len(got.Packs) != 1 || got.Packs[0].Path != want.Packs[0].Path {
+		t.Fatalf("readCache = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadCacheMissing(t *testing.T) {
+	if _, ok := readCache(t.TempDir() + "/does-not-exist.json"); ok {
+		t.Fatal("readCache reported a hit for a file that doesn't exist")
+	}
+}
+
+type stubSearcher struct {
+	name  string
+	packs []pack
+	err   error
+	calls int
+}
+
+func (s *stubSearcher) Name() string { return s.name }
+
+func (s *stubSearcher) Search(q string) ([]pack, error) {
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.packs, nil
+}
+
+func TestCachingSearcherServesFreshEntryWithoutCallingBackend(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	stub := &stubSearcher{name: "stub", packs: []pack{{Path: "example.com/foo"}}}
+	cs := cachingSearcher{Searcher: stub, ttl: time.Hour}
+
+	if _, err := cs.Search("query"); err != nil {
+		t.Fatalf("first Search: %v", err)
+	}
+	if stub.calls != 1 {
+		t.Fatalf("expected 1 backend call after first Search, got %d", stub.calls)
+	}
+
+	if _, err := cs.Search("query"); err != nil {
+		t.Fatalf("second Search: %v", err)
+	}
+	if stub.calls != 1 {
+		t.Fatalf("expected cache hit to avoid a second backend call, got %d calls", stub.calls)
+	}
+}
+
+func TestCachingSearcherRefetchesExpiredEntry(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	stub := &stubSearcher{name: "stub", packs: []pack{{Path: "example.com/foo"}}}
+	cs := cachingSearcher{Searcher: stub, ttl: -time.Second} // already expired
+
+	if _, err := cs.Search("query"); err != nil {
+		t.Fatalf("first Search: %v", err)
+	}
+	if _, err := cs.Search("query"); err != nil {
+		t.Fatalf("second Search: %v", err)
+	}
+	if stub.calls != 2 {
+		t.Fatalf("expected expired entry to trigger a second backend call, got %d calls", stub.calls)
+	}
+}
+
+func TestCachingSearcherRefreshBypassesCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	stub := &stubSearcher{name: "stub", packs: []pack{{Path: "example.com/foo"}}}
+	cs := cachingSearcher{Searcher: stub, ttl: time.Hour, refresh: true}
+
+	if _, err := cs.Search("query"); err != nil {
+		t.Fatalf("first Search: %v", err)
+	}
+	if _, err := cs.Search("query"); err != nil {
+		t.Fatalf("second Search: %v", err)
+	}
+	if stub.calls != 2 {
+		t.Fatalf("expected -refresh to bypass the cache on every call, got %d calls", stub.calls)
+	}
+}
+
+func TestListOnlySearcherFailsWithoutCachedEntry(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	l := listOnlySearcher{&stubSearcher{name: "stub", err: errors.New("network should not be reached")}}
+	if _, err := l.Search("never cached"); err == nil {
+		t.Fatal("expected an error for a query with no cached entry")
+	}
+}
+
+func TestListOnlySearcherReadsCachedEntry(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	stub := &stubSearcher{name: "stub", packs: []pack{{Path: "example.com/foo"}}}
+	cs := cachingSearcher{Searcher: stub, ttl: time.Hour}
+	if _, err := cs.Search("query"); err != nil {
+		t.Fatalf("priming cache: %v", err)
+	}
+
+	l := listOnlySearcher{stub}
+	packs, err := l.Search("query")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(packs) != 1 || packs[0].Path != "example.com/foo" {
+		t.Fatalf("Search = %+v, want the cached pack", packs)
+	}
+}
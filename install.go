@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// goModulePath runs `go env GOMOD` and returns the path to the enclosing
+// module's go.mod, or "" if the current directory isn't inside a module.
+// It's a variable so tests can stub out the exec.Command call.
+var goModulePath = func() (string, error) {
+	out, err := exec.Command("go", "env", "GOMOD").Output()
+	if err != nil {
+		return "", err
+	}
+	return parseGoModPath(string(out)), nil
+}
+
+// parseGoModPath normalizes the output of `go env GOMOD` to "" when it
+// reports no enclosing module, which `go env` spells as an empty line or,
+// on older toolchains, os.DevNull.
+func parseGoModPath(raw string) string {
+	path := strings.TrimSpace(raw)
+	if path == "" || path == os.DevNull {
+		return ""
+	}
+	return path
+}
+
+// isInstalled reports whether importPath is resolvable by the go tool,
+// i.e. `go list` can find a directory for it. This covers both
+// GOPATH-mode packages and module-mode packages already in the module
+// cache.
+func isInstalled(importPath string) bool {
+	out, err := exec.Command("go", "list", "-f", "{{.Dir}}", importPath).Output()
+	if err != nil {
+		return false
+	}
+	return hasDir(string(out))
+}
+
+// hasDir reports whether raw (the output of `go list -f {{.Dir}}`) names
+// a directory rather than being empty.
+func hasDir(raw string) bool {
+	return strings.TrimSpace(raw) != ""
+}
+
+// installPackage installs importPath using module-aware commands: a
+// "go install path@latest" for main packages (-apps), or a "go get" that
+// adds importPath as a dependency of the current module for libraries.
+// If the current directory isn't inside a module, a scratch module is
+// created so "go get" still has somewhere to resolve against, leaving
+// the package in the shared module cache.
+func installPackage(importPath string, apps bool, goflags []string) error {
+	if apps {
+		args := append([]string{"install"}, goflags...)
+		args = append(args, importPath+"@latest")
+		return runGo(args, "")
+	}
+
+	gomod, err := goModulePath()
+	if err != nil {
+		return err
+	}
+	if gomod != "" {
+		args := append([]string{"get"}, goflags...)
+		args = append(args, importPath+"@latest")
+		return runGo(args, "")
+	}
+
+	return installInScratchModule(importPath, goflags)
+}
+
+// installInScratchModule downloads importPath into the module cache via
+// a throwaway module, for installing a library from outside any module.
+func installInScratchModule(importPath string, goflags []string) error {
+	dir, err := ioutil.TempDir("", "gosearch-install")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := runGo([]string{"mod", "init", "gosearch-install"}, dir); err != nil {
+		return err
+	}
+
+	args := append([]string{"get"}, goflags...)
+	args = append(args, importPath+"@latest")
+	return runGo(args, dir)
+}
+
+// runGo runs the go tool with args in dir (the current directory if dir
+// is ""), streaming its output to the terminal. It's a variable so tests
+// can replace it with a fake rather than shelling out for real.
+var runGo = func(args []string, dir string) error {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return fmt.Errorf("could not find go binary in PATH: %w", err)
+	}
+
+	fmt.Println("Install command:", goBin, strings.Join(args, " "))
+
+	cmd := exec.Command(goBin, args...)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
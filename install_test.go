@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseGoModPath(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"", ""},
+		{"\n", ""},
+		{os.DevNull + "\n", ""},
+		{"/home/user/project/go.mod\n", "/home/user/project/go.mod"},
+	}
+	for _, c := range cases {
+		if got := parseGoModPath(c.in); got != c.want {
+			t.Errorf("parseGoModPath(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestHasDir(t *testing.T) {
+	cases := []struct {
+		in   string
+		want bool
+	}{
+		{"", false},
+		{"\n", false},
+		{"/home/user/go/pkg/mod/example.com/foo@v1.0.0\n", true},
+	}
+	for _, c := range cases {
+		if got := hasDir(c.in); got != c.want {
+			t.Errorf("hasDir(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+// withStubbedGo replaces goModulePath and runGo for the duration of a
+// test and restores the originals on cleanup.
+func withStubbedGo(t *testing.T, gomod string, gomodErr error) *[][]string {
+	t.Helper()
+
+	origGoModulePath, origRunGo := goModulePath, runGo
+	t.Cleanup(func() {
+		goModulePath = origGoModulePath
+		runGo = origRunGo
+	})
+
+	goModulePath = func() (string, error) { return gomod, gomodErr }
+
+	var calls [][]string
+	runGo = func(args []string, dir string) error {
+		calls = append(calls, append([]string{dir}, args...))
+		return nil
+	}
+	return &calls
+}
+
+func TestInstallPackageApps(t *testing.T) {
+	calls := withStubbedGo(t, "/home/user/project/go.mod", nil)
+
+	if err := installPackage("example.com/cmd/foo", true, []string{"-v"}); err != nil {
+		t.Fatalf("installPackage: %v", err)
+	}
+	if len(*calls) != 1 {
+		t.Fatalf("runGo called %d times, want 1", len(*calls))
+	}
+	got := (*calls)[0]
+	if got[0] != "" {
+		t.Errorf("dir = %q, want current directory (\"\")", got[0])
+	}
+	joined := strings.Join(got[1:], " ")
+	if !strings.HasPrefix(joined, "install ") || !strings.HasSuffix(joined, "example.com/cmd/foo@latest") {
+		t.Errorf("args = %q, want a \"go install ... @latest\" invocation", joined)
+	}
+}
+
+func TestInstallPackageInModule(t *testing.T) {
+	calls := withStubbedGo(t, "/home/user/project/go.mod", nil)
+
+	if err := installPackage("example.com/foo", false, []string{"-v"}); err != nil {
+		t.Fatalf("installPackage: %v", err)
+	}
+	if len(*calls) != 1 {
+		t.Fatalf("runGo called %d times, want 1", len(*calls))
+	}
+	got := (*calls)[0]
+	if got[0] != "" {
+		t.Errorf("dir = %q, want current directory (\"\")", got[0])
+	}
+	joined := strings.Join(got[1:], " ")
+	if !strings.HasPrefix(joined, "get ") || !strings.HasSuffix(joined, "example.com/foo@latest") {
+		t.Errorf("args = %q, want a \"go get ... @latest\" invocation", joined)
+	}
+}
+
+func TestInstallPackageOutsideModuleUsesScratchModule(t *testing.T) {
+	calls := withStubbedGo(t, "", nil)
+
+	if err := installPackage("example.com/foo", false, []string{"-v"}); err != nil {
+		t.Fatalf("installPackage: %v", err)
+	}
+	if len(*calls) != 2 {
+		t.Fatalf("runGo called %d times, want 2 (mod init, then get)", len(*calls))
+	}
+
+	initCall := (*calls)[0]
+	if initCall[0] == "" {
+		t.Errorf("scratch module init ran in the current directory, want a temp dir")
+	}
+	if strings.Join(initCall[1:], " ") != "mod init gosearch-install" {
+		t.Errorf("first call = %q, want \"go mod init gosearch-install\"", initCall[1:])
+	}
+
+	getCall := (*calls)[1]
+	if getCall[0] != initCall[0] {
+		t.Errorf("get call ran in %q, want the same scratch dir %q", getCall[0], initCall[0])
+	}
+	joined := strings.Join(getCall[1:], " ")
+	if !strings.HasPrefix(joined, "get ") || !strings.HasSuffix(joined, "example.com/foo@latest") {
+		t.Errorf("args = %q, want a \"go get ... @latest\" invocation", joined)
+	}
+}
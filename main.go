@@ -2,18 +2,14 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
-	"errors"
 	"flag"
 	"fmt"
-	"net/http"
-	"net/url"
 	"os"
-	"os/exec"
 	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 )
 
 func main() {
@@ -35,9 +31,17 @@ gosearch [flags] [search term]
 		minImports    = flag.Int("imports", 0, "minimum # of imports for package to be displayed")
 		showInstalled = flag.Bool("installed", false, "mark packeges that are already installed with *")
 		inPath        = flag.Bool("inpath", true, "search term must be in package path")
+		backend       = flag.String("backend", "pkggodev,godoc", "search backend(s) to query, comma-separated (pkggodev, godoc, sourcegraph, github)")
+		cleanSynopsis = flag.Bool("clean-synopsis", true, "trim synopses to their first sentence and drop boilerplate ones")
+		allowBP       = flag.Bool("allow-boilerplate", false, "with -clean-synopsis, keep packs with a Copyright/Autogenerated/etc. synopsis")
+		cacheTTL      = flag.Duration("cache-ttl", 24*time.Hour, "how long cached search results are considered fresh")
+		noCache       = flag.Bool("no-cache", false, "bypass the local result cache entirely")
+		refresh       = flag.Bool("refresh", false, "force a re-fetch of search results, ignoring cache freshness")
+		list          = flag.Bool("list", false, "list cached results only, without querying the network")
+		noTUI         = flag.Bool("no-tui", false, "disable the interactive TUI and use plain text output")
 		goflags       []string
 	)
-	flag.Var((*stringsFlag)(&goflags), "goflags", `arguments to be passed to "go get" (default "-u -v")`)
+	flag.Var((*stringsFlag)(&goflags), "goflags", `arguments to be passed to "go get"/"go install" (default "-v")`)
 	flag.Parse()
 
 	if flag.Arg(0) == "" {
@@ -48,15 +52,33 @@ gosearch [flags] [search term]
 	query := flag.Arg(0)
 
 	if goflags == nil {
-		goflags = []string{"-u", "-v"}
+		goflags = []string{"-v"}
 	}
 
-	matches, err := queryGodoc(query)
+	ss, err := searchersByName(*backend)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	for i, s := range ss {
+		switch {
+		case *list:
+			ss[i] = listOnlySearcher{s}
+		case !*noCache:
+			ss[i] = cachingSearcher{Searcher: s, ttl: *cacheTTL, refresh: *refresh}
+		}
+	}
+
+	matches, err := search(ss, query)
 	if err != nil {
 		fmt.Println(err)
 		return 1
 	}
 
+	if *cleanSynopsis {
+		matches = cleanSynopses(matches, *allowBP)
+	}
+
 	switch {
 	case !*apps:
 		sort.Sort(packsByImports(matches))
@@ -64,10 +86,7 @@ gosearch [flags] [search term]
 		sort.Sort(packsByStars(matches))
 	}
 
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-	fmt.Fprintf(w, "#\tStars\tImports\tPath\tDescription\t\n")
 	var packs []pack
-	var i int
 	for _, p := range matches {
 		if !*forks && p.Fork {
 			continue
@@ -87,29 +106,48 @@ gosearch [flags] [search term]
 		if *inPath && !strings.Contains(p.Path, query) {
 			continue
 		}
-		i++
 
-		path := p.Path
-		if *showInstalled && exec.Command("go", "list", path).Run() == nil {
-			path = "*" + path
-		}
-
-		fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%s\t\n", i, p.Stars, p.ImportCount, path, p.Synopsis)
 		packs = append(packs, p)
 		if *limit > 0 && len(packs) >= *limit {
 			break
 		}
 	}
-	w.Flush()
-	if *showInstalled {
-		fmt.Println("* = installed")
-	}
 
 	if len(packs) == 0 {
 		fmt.Println("No matches.")
 		return 1
 	}
 
+	if !*noTUI && isTerminal(os.Stdout) {
+		result, err := runTUI(packs)
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		for _, importPath := range result.install {
+			fmt.Println("Installing", importPath)
+			if err := installPackage(importPath, *apps, goflags); err != nil {
+				fmt.Println(err)
+				return 1
+			}
+		}
+		return 0
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
+	fmt.Fprintf(w, "#\tStars\tImports\tPath\tDescription\t\n")
+	for i, p := range packs {
+		path := p.Path
+		if *showInstalled && isInstalled(path) {
+			path = "*" + path
+		}
+		fmt.Fprintf(w, "%d\t%d\t%d\t%s\t%s\t\n", i+1, p.Stars, p.ImportCount, path, p.Synopsis)
+	}
+	w.Flush()
+	if *showInstalled {
+		fmt.Println("* = installed")
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	var packN int
 	for {
@@ -135,26 +173,10 @@ gosearch [flags] [search term]
 	importPath := packs[packN-1].Path
 
 	fmt.Println("Installing", importPath)
-
-	goBin, err := exec.LookPath("go")
-	if err != nil {
-		fmt.Println("Could not find go binary in PATH")
-		return 1
-	}
-
-	args := []string{"get"}
-	args = append(args, goflags...)
-	args = append(args, importPath)
-
-	fmt.Println("Install command:", goBin, strings.Join(args, " "))
 	fmt.Println("Press enter to continue...")
 	reader.ReadString('\n')
 
-	cmd := exec.Command(goBin, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	err = cmd.Run()
-	if err != nil {
+	if err := installPackage(importPath, *apps, goflags); err != nil {
 		fmt.Println(err)
 		return 1
 	}
@@ -184,25 +206,6 @@ func (p packsByImports) Len() int           { return len(p) }
 func (p packsByImports) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
 func (p packsByImports) Less(i, j int) bool { return p[i].ImportCount > p[j].ImportCount }
 
-func queryGodoc(q string) ([]pack, error) {
-	resp, err := http.Get("https://api.godoc.org/search?q=" + url.QueryEscape(q))
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.StatusCode != 200 {
-		return nil, errors.New("godoc: " + resp.Status)
-	}
-
-	var results struct{ Results []pack }
-	err = json.NewDecoder(resp.Body).Decode(&results)
-	if err != nil {
-		return nil, err
-	}
-
-	return results.Results, nil
-}
-
 // stringsFlag copied from https://github.com/golang/go/blob/4e584c52036fb2a572fab466e2a291fb695da882/src/cmd/go/build.go
 // Copyright 2011 The Go Authors. All rights reserved.
 type stringsFlag []string
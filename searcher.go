@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Searcher queries a package index for packages matching q.
+type Searcher interface {
+	// Name identifies the backend for the -backend flag.
+	Name() string
+	Search(q string) ([]pack, error)
+}
+
+// searchers are the Searchers known to gosearch, in the order they're
+// queried when multiple backends are selected with -backend.
+var searchers = []Searcher{
+	pkgGoDevSearcher{},
+	godocSearcher{},
+	sourcegraphSearcher{},
+	githubSearcher{},
+}
+
+// searchersByName returns the Searchers named in names, a comma-separated
+// list such as "pkggodev,godoc,sourcegraph,github".
+func searchersByName(names string) ([]Searcher, error) {
+	var selected []Searcher
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		s := searcherNamed(name)
+		if s == nil {
+			return nil, fmt.Errorf("unknown backend %q", name)
+		}
+		selected = append(selected, s)
+	}
+	return selected, nil
+}
+
+func searcherNamed(name string) Searcher {
+	for _, s := range searchers {
+		if s.Name() == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// search queries each of ss and merges the results, deduping by import
+// path. When a pack is returned by more than one backend, fields left
+// zero by the first backend to find it are filled in from later ones.
+func search(ss []Searcher, q string) ([]pack, error) {
+	var (
+		order  []string
+		byPath = make(map[string]pack)
+		errs   []string
+	)
+	for _, s := range ss {
+		found, err := s.Search(q)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", s.Name(), err))
+			continue
+		}
+		for _, p := range found {
+			existing, ok := byPath[p.Path]
+			if !ok {
+				order = append(order, p.Path)
+				byPath[p.Path] = p
+				continue
+			}
+			byPath[p.Path] = mergePacks(existing, p)
+		}
+	}
+	if len(byPath) == 0 && len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+
+	results := make([]pack, 0, len(order))
+	for _, path := range order {
+		results = append(results, byPath[path])
+	}
+	return results, nil
+}
+
+// mergePacks fills zero-valued fields of a with the corresponding field
+// from b, so a pack found by multiple backends accumulates what each one
+// knows about it.
+func mergePacks(a, b pack) pack {
+	if a.Name == "" {
+		a.Name = b.Name
+	}
+	if a.Synopsis == "" {
+		a.Synopsis = b.Synopsis
+	}
+	if !a.Fork {
+		a.Fork = b.Fork
+	}
+	if a.Stars == 0 {
+		a.Stars = b.Stars
+	}
+	if a.ImportCount == 0 {
+		a.ImportCount = b.ImportCount
+	}
+	if a.Score == 0 {
+		a.Score = b.Score
+	}
+	return a
+}
+
+// godocSearcher queries the deprecated api.godoc.org search API. It's kept
+// as a fallback since api.godoc.org may go away entirely, and since it
+// still returns import counts that pkg.go.dev doesn't expose.
+type godocSearcher struct{}
+
+func (godocSearcher) Name() string { return "godoc" }
+
+func (godocSearcher) Search(q string) ([]pack, error) {
+	resp, err := http.Get("https://api.godoc.org/search?q=" + url.QueryEscape(q))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, errors.New("godoc: " + resp.Status)
+	}
+
+	var results struct{ Results []pack }
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+
+	return results.Results, nil
+}
+
+// pkgGoDevSearcher scrapes the pkg.go.dev search results page, which has
+// replaced api.godoc.org as the canonical Go package index. It doesn't
+// expose star or import counts, so it's queried by default alongside
+// godocSearcher to fill those fields in via mergePacks.
+type pkgGoDevSearcher struct{}
+
+func (pkgGoDevSearcher) Name() string { return "pkggodev" }
+
+var pkgGoDevResultRE = regexp.MustCompile(`(?s)<div class="SearchSnippet">.*?href="/([^"]+)".*?</h2>.*?<p class="SearchSnippet-synopsis">(.*?)</p>`)
+
+func (pkgGoDevSearcher) Search(q string) ([]pack, error) {
+	resp, err := http.Get("https://pkg.go.dev/search?q=" + url.QueryEscape(q))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, errors.New("pkg.go.dev: " + resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []pack
+	for _, m := range pkgGoDevResultRE.FindAllStringSubmatch(string(body), -1) {
+		packs = append(packs, pack{
+			Path:     strings.TrimSpace(m[1]),
+			Synopsis: cleanHTML(m[2]),
+		})
+	}
+
+	return packs, nil
+}
+
+var htmlTagRE = regexp.MustCompile(`<[^>]*>`)
+
+func cleanHTML(s string) string {
+	return strings.TrimSpace(htmlTagRE.ReplaceAllString(s, ""))
+}
+
+// sourcegraphSearcher queries Sourcegraph's public code search, which
+// indexes repositories pkg.go.dev hasn't gotten to yet and is often
+// faster to pick up brand-new or private-registry-adjacent packages.
+type sourcegraphSearcher struct{}
+
+func (sourcegraphSearcher) Name() string { return "sourcegraph" }
+
+const sourcegraphSearchQuery = `query Search($query: String!) {
+	search(query: $query, version: V3) {
+		results {
+			results {
+				... on Repository {
+					name
+					description
+				}
+			}
+		}
+	}
+}`
+
+func (sourcegraphSearcher) Search(q string) ([]pack, error) {
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"query": sourcegraphSearchQuery,
+		"variables": map[string]string{
+			"query": "type:repo lang:go " + q,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.Post("https://sourcegraph.com/.api/graphql", "application/json", bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, errors.New("sourcegraph: " + resp.Status)
+	}
+
+	var result struct {
+		Data struct {
+			Search struct {
+				Results struct {
+					Results []struct {
+						Name        string `json:"name"`
+						Description string `json:"description"`
+					} `json:"results"`
+				} `json:"results"`
+			} `json:"search"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	packs := make([]pack, 0, len(result.Data.Search.Results.Results))
+	for _, r := range result.Data.Search.Results.Results {
+		if r.Name == "" {
+			continue
+		}
+		packs = append(packs, pack{
+			Path:     r.Name,
+			Synopsis: r.Description,
+		})
+	}
+	return packs, nil
+}
+
+// githubSearcher uses the GitHub code search API to find repositories
+// tagged with the go/golang topic, for cases where a package hasn't been
+// indexed by pkg.go.dev (e.g. it's unpublished or very new).
+type githubSearcher struct{}
+
+func (githubSearcher) Name() string { return "github" }
+
+func (githubSearcher) Search(q string) ([]pack, error) {
+	query := url.QueryEscape(q + " topic:go topic:golang")
+	resp, err := http.Get("https://api.github.com/search/repositories?q=" + query + "&sort=stars")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, errors.New("github: " + resp.Status)
+	}
+
+	var results struct {
+		Items []struct {
+			FullName        string `json:"full_name"`
+			Description     string `json:"description"`
+			Fork            bool   `json:"fork"`
+			StargazersCount int    `json:"stargazers_count"`
+		}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, err
+	}
+
+	packs := make([]pack, 0, len(results.Items))
+	for _, item := range results.Items {
+		packs = append(packs, pack{
+			Path:     "github.com/" + item.FullName,
+			Synopsis: item.Description,
+			Fork:     item.Fork,
+			Stars:    item.StargazersCount,
+		})
+	}
+
+	return packs, nil
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSearchersByNameUnknown(t *testing.T) {
+	if _, err := searchersByName("not-a-real-backend"); err == nil {
+		t.Fatal("searchersByName returned no error for an unknown backend name")
+	}
+}
+
+func TestSearchersByNameKnown(t *testing.T) {
+	ss, err := searchersByName("pkggodev,godoc")
+	if err != nil {
+		t.Fatalf("searchersByName: %v", err)
+	}
+	if len(ss) != 2 || ss[0].Name() != "pkggodev" || ss[1].Name() != "godoc" {
+		t.Fatalf("searchersByName(\"pkggodev,godoc\") = %+v, want [pkggodev godoc]", ss)
+	}
+}
+
+type fakeSearcher struct {
+	name  string
+	packs []pack
+	err   error
+}
+
+func (f fakeSearcher) Name() string { return f.name }
+
+func (f fakeSearcher) Search(q string) ([]pack, error) {
+	return f.packs, f.err
+}
+
+func TestSearchMergesComplementaryFields(t *testing.T) {
+	a := fakeSearcher{name: "a", packs: []pack{{Path: "example.com/foo", Synopsis: "a description"}}}
+	b := fakeSearcher{name: "b", packs: []pack{{Path: "example.com/foo", Stars: 42, ImportCount: 7}}}
+
+	got, err := search([]Searcher{a, b}, "foo")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	want := []pack{{Path: "example.com/foo", Synopsis: "a description", Stars: 42, ImportCount: 7}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("search merged = %+v, want %+v", got, want)
+	}
+}
+
+func TestSearchDedupesPreservingFirstSeenOrder(t *testing.T) {
+	a := fakeSearcher{name: "a", packs: []pack{{Path: "example.com/foo"}, {Path: "example.com/bar"}}}
+	b := fakeSearcher{name: "b", packs: []pack{{Path: "example.com/bar"}, {Path: "example.com/baz"}}}
+
+	got, err := search([]Searcher{a, b}, "q")
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+
+	var paths []string
+	for _, p := range got {
+		paths = append(paths, p.Path)
+	}
+	want := []string{"example.com/foo", "example.com/bar", "example.com/baz"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("search order = %v, want %v", paths, want)
+	}
+}
+
+func TestSearchReturnsErrorOnlyWhenEveryBackendFails(t *testing.T) {
+	ok := fakeSearcher{name: "ok", packs: []pack{{Path: "example.com/foo"}}}
+	failing := fakeSearcher{name: "failing", err: errors.New("boom")}
+
+	// One backend failing shouldn't surface an error as long as another
+	// backend returned results.
+	got, err := search([]Searcher{ok, failing}, "q")
+	if err != nil {
+		t.Fatalf("search with one failing backend returned an error: %v", err)
+	}
+	if len(got) != 1 || got[0].Path != "example.com/foo" {
+		t.Fatalf("search = %+v, want the surviving backend's results", got)
+	}
+
+	// Every backend failing should surface the joined errors.
+	_, err = search([]Searcher{failing, failing}, "q")
+	if err == nil {
+		t.Fatal("search with every backend failing returned no error")
+	}
+}
+
+func TestMergePacksFillsZeroFieldsOnly(t *testing.T) {
+	a := pack{Path: "p", Name: "p", Stars: 5}
+	b := pack{Path: "p", Name: "overwritten", Stars: 99, ImportCount: 3, Synopsis: "desc"}
+
+	got := mergePacks(a, b)
+	want := pack{Path: "p", Name: "p", Stars: 5, ImportCount: 3, Synopsis: "desc"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("mergePacks(%+v, %+v) = %+v, want %+v", a, b, got, want)
+	}
+}
+
+func TestAllBackendNamesAreUnique(t *testing.T) {
+	seen := make(map[string]bool)
+	var names []string
+	for _, s := range searchers {
+		if seen[s.Name()] {
+			t.Fatalf("duplicate backend name %q", s.Name())
+		}
+		seen[s.Name()] = true
+		names = append(names, s.Name())
+	}
+	sort.Strings(names)
+	want := []string{"github", "godoc", "pkggodev", "sourcegraph"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("registered backend names = %v, want %v", names, want)
+	}
+}
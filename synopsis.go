@@ -0,0 +1,89 @@
+package main
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// boilerplatePrefixes mark a synopsis as auto-generated or license text
+// rather than an actual package description.
+var boilerplatePrefixes = []string{
+	"Copyright",
+	"COPYRIGHT",
+	"Autogenerated",
+	"Auto-generated",
+	"Automatically generated",
+	"TODO:",
+	"vim:",
+	`THE SOFTWARE IS PROVIDED "AS IS"`,
+}
+
+// maxSynopsisLen caps a cleaned synopsis so a single long-winded pack
+// doesn't blow out the results column.
+const maxSynopsisLen = 400
+
+// cleanSynopses trims each pack's synopsis to its first sentence and caps
+// its length. Unless allowBoilerplate is set, packs whose synopsis looks
+// auto-generated or like license text are dropped entirely.
+func cleanSynopses(packs []pack, allowBoilerplate bool) []pack {
+	cleaned := make([]pack, 0, len(packs))
+	for _, p := range packs {
+		p.Synopsis = cleanSynopsis(p.Synopsis)
+		if !allowBoilerplate && isBoilerplate(p.Synopsis) {
+			continue
+		}
+		cleaned = append(cleaned, p)
+	}
+	return cleaned
+}
+
+func isBoilerplate(s string) bool {
+	for _, prefix := range boilerplatePrefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanSynopsis collapses whitespace runs to a single space and trims the
+// result to its first sentence and maxSynopsisLen bytes.
+func cleanSynopsis(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = firstSentence(s)
+	return truncateSynopsis(s, maxSynopsisLen)
+}
+
+// firstSentence returns s up to and including its first ".", "!", or "?",
+// or all of s if it has no sentence terminator.
+func firstSentence(s string) string {
+	i := strings.IndexAny(s, ".!?")
+	if i == -1 {
+		return s
+	}
+	return s[:i+1]
+}
+
+// truncateSynopsis cuts s to at most max bytes, breaking on the last
+// space so words aren't split, and appends "...". The cut point is
+// backed up to a rune boundary first so a multi-byte UTF-8 synopsis
+// isn't sliced mid-rune.
+func truncateSynopsis(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	max = prevRuneBoundary(s, max)
+	cut := strings.LastIndex(s[:max], " ")
+	if cut == -1 {
+		cut = max
+	}
+	return strings.TrimSpace(s[:cut]) + "..."
+}
+
+// prevRuneBoundary returns the largest rune-boundary offset <= i in s.
+func prevRuneBoundary(s string, i int) int {
+	for i > 0 && !utf8.RuneStart(s[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCleanSynopsis(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"A  package   for\n\tdoing things. More here.", "A package for doing things."},
+		{"no terminator here", "no terminator here"},
+		{"  leading and trailing  whitespace  ", "leading and trailing whitespace"},
+	}
+	for _, c := range cases {
+		if got := cleanSynopsis(c.in); got != c.want {
+			t.Errorf("cleanSynopsis(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestIsBoilerplate(t *testing.T) {
+	cases := []struct {
+		in   string
+		want bool
+	}{
+		{"Copyright 2020 The Go Authors.", true},
+		{"Autogenerated by go-bindata.", true},
+		{`THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY.`, true},
+		{"A perfectly normal synopsis.", false},
+	}
+	for _, c := range cases {
+		if got := isBoilerplate(c.in); got != c.want {
+			t.Errorf("isBoilerplate(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestCleanSynopsesDropsBoilerplate(t *testing.T) {
+	packs := []pack{
+		{Path: "a", Synopsis: "Copyright 2020, do not use."},
+		{Path: "b", Synopsis: "A real description."},
+	}
+
+	cleaned := cleanSynopses(packs, false)
+	if len(cleaned) != 1 || cleaned[0].Path != "b" {
+		t.Fatalf("cleanSynopses(allowBoilerplate=false) = %+v, want only pack b", cleaned)
+	}
+
+	kept := cleanSynopses(packs, true)
+	if len(kept) != 2 {
+		t.Fatalf("cleanSynopses(allowBoilerplate=true) = %+v, want both packs kept", kept)
+	}
+}
+
+func TestTruncateSynopsis(t *testing.T) {
+	short := "short synopsis"
+	if got := truncateSynopsis(short, 400); got != short {
+		t.Errorf("truncateSynopsis(%q, 400) = %q, want unchanged", short, got)
+	}
+
+	long := strings.Repeat("word ", 100)
+	got := truncateSynopsis(long, 20)
+	if len(got) > 23 { // 20 + "..." plus a little slack for the space trim
+		t.Errorf("truncateSynopsis did not cap length: got %d bytes: %q", len(got), got)
+	}
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("truncateSynopsis(%q, 20) = %q, want it to end with ...", long, got)
+	}
+
+	// A multi-byte rune sitting right at the cut point must not be split,
+	// which would otherwise produce invalid UTF-8.
+	multibyte := strings.Repeat("a", 18) + "日本語" + strings.Repeat("b", 20)
+	got = truncateSynopsis(multibyte, 20)
+	if !strings.HasSuffix(got, "...") {
+		t.Fatalf("truncateSynopsis(%q, 20) = %q, want it to end with ...", multibyte, got)
+	}
+	if body := strings.TrimSuffix(got, "..."); !strings.Contains(multibyte, body) {
+		t.Errorf("truncateSynopsis(%q, 20) = %q, not valid prefix of input", multibyte, got)
+	}
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	headerStyle   = lipgloss.NewStyle().Bold(true).Underline(true)
+	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	cursorStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("6")).Bold(true)
+	previewStyle  = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+)
+
+// tuiResult is what runTUI returns: the import paths the user queued for
+// installation, in the order they appear in the result list.
+type tuiResult struct {
+	install []string
+}
+
+// previewMsg carries the outcome of a lazily-loaded README fetch back to
+// the Update loop.
+type previewMsg struct {
+	path string
+	body string
+	err  error
+}
+
+// tuiModel is the bubbletea model backing the interactive results list.
+type tuiModel struct {
+	all     []pack
+	visible []pack
+	cursor  int
+
+	selected map[string]bool
+
+	filtering bool
+	filter    string
+
+	showStars   bool
+	showImports bool
+	showScore   bool
+
+	showPreview bool
+	preview     map[string]string
+	previewErr  map[string]string
+}
+
+func newTUIModel(packs []pack) tuiModel {
+	return tuiModel{
+		all:         packs,
+		visible:     packs,
+		selected:    make(map[string]bool),
+		showStars:   true,
+		showImports: true,
+		preview:     make(map[string]string),
+		previewErr:  make(map[string]string),
+		showPreview: true,
+	}
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return m.loadPreview()
+}
+
+func (m tuiModel) current() (pack, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return pack{}, false
+	}
+	return m.visible[m.cursor], true
+}
+
+// loadPreview returns a command that fetches the README for the package
+// under the cursor, unless it's already loaded, already failed, or the
+// preview pane is hidden.
+func (m tuiModel) loadPreview() tea.Cmd {
+	p, ok := m.current()
+	if !ok || !m.showPreview {
+		return nil
+	}
+	if _, ok := m.preview[p.Path]; ok {
+		return nil
+	}
+	if _, ok := m.previewErr[p.Path]; ok {
+		return nil
+	}
+
+	path := p.Path
+	return func() tea.Msg {
+		body, err := fetchReadme(path)
+		return previewMsg{path: path, body: body, err: err}
+	}
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case previewMsg:
+		if msg.err != nil {
+			m.previewErr[msg.path] = msg.err.Error()
+		} else {
+			m.preview[msg.path] = msg.body
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.filtering {
+			return m.updateFiltering(msg)
+		}
+		return m.updateNormal(msg)
+	}
+
+	return m, nil
+}
+
+func (m tuiModel) updateFiltering(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEnter, tea.KeyEsc:
+		m.filtering = false
+	case tea.KeyBackspace:
+		if len(m.filter) > 0 {
+			m.filter = m.filter[:len(m.filter)-1]
+		}
+	case tea.KeyRunes:
+		m.filter += string(msg.Runes)
+	default:
+		return m, nil
+	}
+	m.applyFilter()
+	return m, m.loadPreview()
+}
+
+func (m tuiModel) updateNormal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q":
+		m.selected = nil
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, m.loadPreview()
+	case "down", "j":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+		}
+		return m, m.loadPreview()
+	case "/":
+		m.filtering = true
+		return m, nil
+	case " ":
+		if p, ok := m.current(); ok {
+			if m.selected[p.Path] {
+				delete(m.selected, p.Path)
+			} else {
+				m.selected[p.Path] = true
+			}
+		}
+		return m, nil
+	case "p":
+		m.showPreview = !m.showPreview
+		return m, m.loadPreview()
+	case "1":
+		m.showStars = !m.showStars
+		return m, nil
+	case "2":
+		m.showImports = !m.showImports
+		return m, nil
+	case "3":
+		m.showScore = !m.showScore
+		return m, nil
+	case "enter":
+		if len(m.selected) == 0 {
+			if p, ok := m.current(); ok {
+				m.selected[p.Path] = true
+			}
+		}
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m *tuiModel) applyFilter() {
+	if m.filter == "" {
+		m.visible = m.all
+	} else {
+		var visible []pack
+		for _, p := range m.all {
+			if strings.Contains(p.Path, m.filter) {
+				visible = append(visible, p)
+			}
+		}
+		m.visible = visible
+	}
+	if m.cursor >= len(m.visible) {
+		m.cursor = len(m.visible) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m tuiModel) View() string {
+	var b strings.Builder
+
+	header := "Sel "
+	if m.showStars {
+		header += fmt.Sprintf("%-7s", "Stars")
+	}
+	if m.showImports {
+		header += fmt.Sprintf("%-9s", "Imports")
+	}
+	if m.showScore {
+		header += fmt.Sprintf("%-7s", "Score")
+	}
+	header += "Path"
+	fmt.Fprintln(&b, headerStyle.Render(header))
+
+	for i, p := range m.visible {
+		mark := " "
+		if m.selected[p.Path] {
+			mark = "x"
+		}
+		prefix := "  "
+		if i == m.cursor {
+			prefix = cursorStyle.Render("> ")
+		}
+		line := prefix + fmt.Sprintf("[%s] ", mark)
+		if m.showStars {
+			line += fmt.Sprintf("%-6d ", p.Stars)
+		}
+		if m.showImports {
+			line += fmt.Sprintf("%-8d ", p.ImportCount)
+		}
+		if m.showScore {
+			line += fmt.Sprintf("%-6.1f ", p.Score)
+		}
+		line += p.Path
+		if m.selected[p.Path] {
+			line = selectedStyle.Render(line)
+		}
+		fmt.Fprintln(&b, line)
+	}
+
+	switch {
+	case m.filtering:
+		fmt.Fprintf(&b, "\n/%s", m.filter)
+	default:
+		fmt.Fprint(&b, "\n↑/↓ navigate  space select  enter install  / filter  p preview  1/2/3 toggle stars/imports/score  q quit")
+	}
+
+	if m.showPreview {
+		if p, ok := m.current(); ok {
+			fmt.Fprint(&b, "\n\n"+previewStyle.Render(p.Synopsis+"\n\n"+m.previewText(p.Path)))
+		}
+	}
+
+	return b.String()
+}
+
+func (m tuiModel) previewText(path string) string {
+	if errMsg, ok := m.previewErr[path]; ok {
+		return "error loading preview: " + errMsg
+	}
+	if body, ok := m.preview[path]; ok {
+		return body
+	}
+	return "loading..."
+}
+
+func (m tuiModel) result() tuiResult {
+	paths := make([]string, 0, len(m.selected))
+	for path := range m.selected {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return tuiResult{install: paths}
+}
+
+// runTUI displays packs in an interactive terminal UI and returns the
+// import paths the user queued for installation.
+func runTUI(packs []pack) (tuiResult, error) {
+	final, err := tea.NewProgram(newTUIModel(packs)).Run()
+	if err != nil {
+		return tuiResult{}, err
+	}
+	return final.(tuiModel).result(), nil
+}
+
+// isTerminal reports whether f looks like an interactive terminal rather
+// than a pipe or redirected file.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+var readmeRE = regexp.MustCompile(`(?s)<div class="Documentation-readme">(.*?)</div>\s*</div>`)
+
+// fetchReadme lazily loads a package's README from pkg.go.dev for the TUI
+// preview pane.
+func fetchReadme(path string) (string, error) {
+	resp, err := http.Get("https://pkg.go.dev/" + path + "?tab=readme")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("pkg.go.dev: %s", resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	m := readmeRE.FindStringSubmatch(string(body))
+	if m == nil {
+		return "(no README found)", nil
+	}
+	return cleanHTML(m[1]), nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSpaceSelectThenDeselectLeavesNothingSelected(t *testing.T) {
+	m := newTUIModel([]pack{{Path: "a"}, {Path: "b"}})
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = model.(tuiModel)
+	if len(m.selected) != 1 || !m.selected["a"] {
+		t.Fatalf("after select: selected = %v, want {a: true}", m.selected)
+	}
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = model.(tuiModel)
+	if len(m.selected) != 0 {
+		t.Fatalf("after deselect: selected = %v, want empty (regression: stale false entry)", m.selected)
+	}
+
+	if got := m.result(); len(got.install) != 0 {
+		t.Fatalf("result() = %+v, want no installs queued", got)
+	}
+}
+
+func TestApplyFilterNarrowsVisibleAndClampsCursor(t *testing.T) {
+	m := newTUIModel([]pack{
+		{Path: "example.com/foo"},
+		{Path: "example.com/bar"},
+		{Path: "other.com/baz"},
+	})
+	m.cursor = 2 // on other.com/baz, which the filter below excludes
+
+	m.filter = "example.com"
+	m.applyFilter()
+
+	if len(m.visible) != 2 {
+		t.Fatalf("visible = %+v, want 2 packs matching the filter", m.visible)
+	}
+	for _, p := range m.visible {
+		if !strings.Contains(p.Path, "example.com") {
+			t.Errorf("visible contains non-matching pack %+v", p)
+		}
+	}
+	if want := len(m.visible) - 1; m.cursor != want {
+		t.Errorf("cursor = %d, want clamped to %d", m.cursor, want)
+	}
+}
+
+func TestApplyFilterEmptyResetsToAll(t *testing.T) {
+	m := newTUIModel([]pack{{Path: "a"}, {Path: "b"}})
+	m.filter = "a"
+	m.applyFilter()
+	m.filter = ""
+	m.applyFilter()
+
+	if len(m.visible) != len(m.all) {
+		t.Fatalf("visible = %+v, want all %d packs restored", m.visible, len(m.all))
+	}
+}
+
+func TestEnterDefaultsToCurrentItemWhenNothingSelected(t *testing.T) {
+	m := newTUIModel([]pack{{Path: "a"}, {Path: "b"}})
+	m.cursor = 1 // "b"
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(tuiModel)
+	if cmd == nil {
+		t.Fatal("enter should return tea.Quit")
+	}
+
+	got := m.result()
+	if len(got.install) != 1 || got.install[0] != "b" {
+		t.Fatalf("result() = %+v, want [b] (the item under the cursor)", got)
+	}
+}
+
+func TestEnterDoesNotOverrideExplicitSelection(t *testing.T) {
+	m := newTUIModel([]pack{{Path: "a"}, {Path: "b"}})
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace}) // select "a" at cursor 0
+	m = model.(tuiModel)
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown}) // move cursor to "b"
+	m = model.(tuiModel)
+
+	model, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(tuiModel)
+
+	got := m.result()
+	if len(got.install) != 1 || got.install[0] != "a" {
+		t.Fatalf("result() = %+v, want only the explicitly selected \"a\"", got)
+	}
+}
+
+func TestQuitClearsSelection(t *testing.T) {
+	m := newTUIModel([]pack{{Path: "a"}})
+
+	model, _ := m.Update(tea.KeyMsg{Type: tea.KeySpace})
+	m = model.(tuiModel)
+
+	model, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	m = model.(tuiModel)
+	if cmd == nil {
+		t.Fatal("q should return tea.Quit")
+	}
+	if got := m.result(); len(got.install) != 0 {
+		t.Fatalf("result() after q = %+v, want nothing queued for install", got)
+	}
+}